@@ -0,0 +1,14 @@
+package acme
+
+// Account is a subset of the ACME Account resource (RFC 8555 §7.1.2): just
+// enough for the order-listing link to be populated. The rest of the
+// account fields (Key, Contact, Status, ...) and the account handlers that
+// set them live alongside this file in the full repository and are not
+// reproduced in this trimmed checkout.
+type Account struct {
+	ID string `json:"-"`
+	// OrdersURL is the account's "orders" URL from RFC 8555 §7.1.2,
+	// populated by Linker.LinkAccount so that a GetOrUpdateAccount response
+	// tells the client where to list its orders.
+	OrdersURL string `json:"orders"`
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+
+	"github.com/smallstep/certificates/acme"
+)
+
+// ContextKey is the key type for storing and searching for ACME request
+// essentials in the context of a request. The jws/jwk keys used by the
+// request-authentication middleware live alongside this file in the full
+// repository and aren't reproduced in this trimmed checkout.
+type ContextKey string
+
+const (
+	// accContextKey is the context key for the authenticated ACME account.
+	accContextKey = ContextKey("acc")
+	// payloadContextKey is the context key for the decoded JWS payload.
+	payloadContextKey = ContextKey("payload")
+)
+
+// payloadInfo holds the raw decoded body of a signed ACME request.
+type payloadInfo struct {
+	value []byte
+}
+
+// accountFromContext searches the context for an ACME account. Returns the
+// account or an error.
+func accountFromContext(ctx context.Context) (*acme.Account, error) {
+	val, ok := ctx.Value(accContextKey).(*acme.Account)
+	if !ok || val == nil {
+		return nil, acme.NewError(acme.ErrorAccountDoesNotExistType, "account not in context")
+	}
+	return val, nil
+}
+
+// provisionerFromContext searches the context for a provisioner. Returns the
+// provisioner or an error.
+func provisionerFromContext(ctx context.Context) (acme.Provisioner, error) {
+	p, ok := acme.ProvisionerFromContext(ctx)
+	if !ok || p == nil {
+		return nil, acme.NewErrorISE("provisioner expected in request context")
+	}
+	return p, nil
+}
+
+// payloadFromContext searches the context for a decoded JWS payload. Returns
+// the payload or an error.
+func payloadFromContext(ctx context.Context) (*payloadInfo, error) {
+	val, ok := ctx.Value(payloadContextKey).(*payloadInfo)
+	if !ok || val == nil {
+		return nil, acme.NewErrorISE("payload expected in request context")
+	}
+	return val, nil
+}
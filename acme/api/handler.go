@@ -0,0 +1,28 @@
+package api
+
+import (
+	"time"
+
+	"github.com/go-chi/chi"
+
+	"github.com/smallstep/certificates/acme"
+)
+
+// clock returns time in UTC truncated to seconds, so that it round-trips
+// cleanly through the JSON/DB layer.
+type clockwork struct{}
+
+func (clockwork) Now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+var clock clockwork
+
+// RegisterOrdersByAccountRoute wires the orders-by-account listing endpoint
+// under the account's URL. It is meant to be composed into the same router
+// that registers the rest of the ACME API (new-order, order, finalize, ...);
+// that wiring lives outside this trimmed checkout and isn't reproduced here.
+func RegisterOrdersByAccountRoute(r chi.Router) {
+	r.MethodFunc("GET", acme.GetUnescapedPathSuffix(acme.OrdersByAccountLinkType, "{provisionerID}", "{accID}"),
+		GetOrdersByAccountID)
+}
@@ -5,12 +5,17 @@ import (
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"net"
 	"net/http"
+	"net/netip"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-chi/chi"
+	"golang.org/x/net/idna"
 
 	"go.step.sm/crypto/randutil"
 
@@ -25,22 +30,74 @@ type NewOrderRequest struct {
 	NotAfter    time.Time         `json:"notAfter,omitempty"`
 }
 
-// Validate validates a new-order request body.
+// Validate validates a new-order request body. As a side effect it
+// canonicalizes n.Identifiers: DNS values are IDNA-converted and lowercased,
+// IP values are normalized to their canonical net/netip form, duplicates
+// (compared by canonical Type+Value) are dropped, and the result is sorted
+// so that two requests differing only in order or casing produce the same
+// identifier set.
 func (n *NewOrderRequest) Validate() error {
 	if len(n.Identifiers) == 0 {
 		return acme.NewError(acme.ErrorMalformedType, "identifiers list cannot be empty")
 	}
+
+	seen := make(map[acme.Identifier]struct{}, len(n.Identifiers))
+	canonical := make([]acme.Identifier, 0, len(n.Identifiers))
 	for _, id := range n.Identifiers {
-		if !(id.Type == acme.DNS || id.Type == acme.IP) {
+		switch id.Type {
+		case acme.DNS:
+			value, err := toASCIIIdentifier(id.Value)
+			if err != nil {
+				return acme.WrapError(acme.ErrorMalformedType, err, "invalid DNS identifier %s", id.Value)
+			}
+			id.Value = value
+		case acme.IP:
+			ip := net.ParseIP(id.Value)
+			if ip == nil {
+				return acme.NewError(acme.ErrorMalformedType, "invalid IP address: %s", id.Value)
+			}
+			addr, ok := netip.AddrFromSlice(ip.To16())
+			if !ok {
+				return acme.NewError(acme.ErrorMalformedType, "invalid IP address: %s", id.Value)
+			}
+			id.Value = addr.Unmap().String()
+		default:
 			return acme.NewError(acme.ErrorMalformedType, "identifier type unsupported: %s", id.Type)
 		}
-		if id.Type == acme.IP && net.ParseIP(id.Value) == nil {
-			return acme.NewError(acme.ErrorMalformedType, "invalid IP address: %s", id.Value)
+		if _, ok := seen[id]; ok {
+			continue
 		}
+		seen[id] = struct{}{}
+		canonical = append(canonical, id)
 	}
+
+	sort.Slice(canonical, func(i, j int) bool {
+		if canonical[i].Type != canonical[j].Type {
+			return canonical[i].Type < canonical[j].Type
+		}
+		return canonical[i].Value < canonical[j].Value
+	})
+	n.Identifiers = canonical
+
 	return nil
 }
 
+// toASCIIIdentifier converts a DNS identifier to its canonical ASCII/A-label
+// form, lowercasing the result and preserving a leading wildcard label (which
+// is not itself a valid IDNA label and must be stripped before conversion).
+func toASCIIIdentifier(value string) (string, error) {
+	label := strings.TrimPrefix(value, "*.")
+	ascii, err := idna.Lookup.ToASCII(label)
+	if err != nil {
+		return "", err
+	}
+	ascii = strings.ToLower(ascii)
+	if label != value {
+		ascii = "*." + ascii
+	}
+	return ascii, nil
+}
+
 // FinalizeRequest captures the body for a Finalize order request.
 type FinalizeRequest struct {
 	CSR string `json:"csr"`
@@ -67,6 +124,16 @@ func (f *FinalizeRequest) Validate() error {
 var defaultOrderExpiry = time.Hour * 24
 var defaultOrderBackdate = time.Minute
 
+// defaultACMECertMaxDuration is the hard upper bound on the lifetime of a
+// certificate issued through ACME when the provisioner does not set its own
+// MaxTLSCertDuration.
+var defaultACMECertMaxDuration = 24 * time.Hour * 90
+
+// defaultOrderNotBeforeSkew bounds how far into the future a client-supplied
+// NotBefore may be before the order is rejected outright, used when the
+// provisioner does not configure its own NotBeforeSkew.
+var defaultOrderNotBeforeSkew = time.Hour
+
 // NewOrder ACME api for creating a new order.
 func NewOrder(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -100,6 +167,22 @@ func NewOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// RFC 8555 §7.4 allows a server to return an existing order instead of
+	// creating a duplicate. Reusing a pending/ready order for an identical
+	// identifier set spares the account a fresh set of authorizations and
+	// challenge tokens every time it retries the same request.
+	existing, err := findExistingOrder(ctx, db, acc.ID, prov.GetID(), nor.Identifiers)
+	if err != nil {
+		render.Error(w, acme.WrapErrorISE(err, "error checking for existing order"))
+		return
+	}
+	if existing != nil {
+		linker.LinkOrder(ctx, existing)
+		w.Header().Set("Location", linker.GetLink(ctx, acme.OrderLinkType, existing.ID))
+		render.JSONStatus(w, existing, http.StatusCreated)
+		return
+	}
+
 	now := clock.Now()
 	// New order.
 	o := &acme.Order{
@@ -139,6 +222,11 @@ func NewOrder(w http.ResponseWriter, r *http.Request) {
 		o.NotBefore = o.NotBefore.Add(-defaultOrderBackdate)
 	}
 
+	if err := capCertValidityWindow(o, prov, now); err != nil {
+		render.Error(w, err)
+		return
+	}
+
 	if err := db.CreateOrder(ctx, o); err != nil {
 		render.Error(w, acme.WrapErrorISE(err, "error creating order"))
 		return
@@ -231,6 +319,76 @@ func GetOrder(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, o)
 }
 
+// ordersByAccountIDPageSize is the number of the account's order IDs that
+// GetOrdersByAccountID reads per page. Expired and invalid orders among them
+// are dropped, so a page holds at most this many order URLs.
+const ordersByAccountIDPageSize = 50
+
+// GetOrdersByAccountID ACME api for retrieving the list of an account's
+// orders, per RFC 8555 section 7.1.2.1.
+func GetOrdersByAccountID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	db := acme.MustDatabaseFromContext(ctx)
+	linker := acme.MustLinkerFromContext(ctx)
+
+	acc, err := accountFromContext(ctx)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+	accID := chi.URLParam(r, "accID")
+	if acc.ID != accID {
+		render.Error(w, acme.NewError(acme.ErrorUnauthorizedType,
+			"account '%s' does not own orders for account '%s'", acc.ID, accID))
+		return
+	}
+
+	oids, err := db.GetOrdersByAccountID(ctx, accID)
+	if err != nil {
+		render.Error(w, acme.WrapErrorISE(err, "error retrieving orders for account %s", accID))
+		return
+	}
+
+	start := 0
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		start, err = strconv.Atoi(cursor)
+		if err != nil || start < 0 || start > len(oids) {
+			render.Error(w, acme.NewError(acme.ErrorMalformedType, "invalid cursor '%s'", cursor))
+			return
+		}
+	}
+	end := start + ordersByAccountIDPageSize
+	if end > len(oids) {
+		end = len(oids)
+	}
+
+	now := clock.Now()
+	orderURLs := make([]string, 0, end-start)
+	for _, oid := range oids[start:end] {
+		o, err := db.GetOrder(ctx, oid)
+		if err != nil {
+			render.Error(w, acme.WrapErrorISE(err, "error retrieving order %s for account %s", oid, accID))
+			return
+		}
+		// RFC 8555 §7.1.2.1: invalid and expired orders are filtered out;
+		// orders of every other status are listed.
+		if o.Status == acme.StatusInvalid || o.ExpiresAt.Before(now) {
+			continue
+		}
+		linker.LinkOrder(ctx, o)
+		orderURLs = append(orderURLs, linker.GetLink(ctx, acme.OrderLinkType, o.ID))
+	}
+
+	if end < len(oids) {
+		next := linker.GetLink(ctx, acme.OrdersByAccountLinkType, accID)
+		w.Header().Add("Link", fmt.Sprintf(`<%s?cursor=%d>; rel="next"`, next, end))
+	}
+
+	render.JSON(w, &struct {
+		Orders []string `json:"orders"`
+	}{Orders: orderURLs})
+}
+
 // FinalizeOrder attemptst to finalize an order and create a certificate.
 func FinalizeOrder(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -279,6 +437,14 @@ func FinalizeOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The cap is enforced again here, rather than trusting the window stored
+	// on the order, so that tightening a provisioner's MaxTLSCertDuration
+	// takes effect for orders that were created under a looser config.
+	if err := capCertValidityWindow(o, prov, clock.Now()); err != nil {
+		render.Error(w, err)
+		return
+	}
+
 	ca := mustAuthority(ctx)
 	if err = o.Finalize(ctx, db, fr.csr, ca, prov); err != nil {
 		render.Error(w, acme.WrapErrorISE(err, "error finalizing order"))
@@ -291,6 +457,95 @@ func FinalizeOrder(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, o)
 }
 
+// findExistingOrder looks for a non-expired pending or ready order belonging
+// to (accID, provID) whose identifier set is identical to ids. ids is
+// expected to already be canonicalized, deduplicated, and sorted by
+// NewOrderRequest.Validate.
+//
+// provID must match: an account can hold orders under several ACME
+// provisioners on the same CA, and GetOrder rejects access to an order whose
+// ProvisionerID doesn't match the caller's, so handing back an order created
+// under a different provisioner would produce a Location the client can't
+// subsequently fetch.
+func findExistingOrder(ctx context.Context, db acme.DB, accID, provID string, ids []acme.Identifier) (*acme.Order, error) {
+	oids, err := db.GetOrdersByAccountID(ctx, accID)
+	if err != nil {
+		return nil, err
+	}
+	now := clock.Now()
+	for _, oid := range oids {
+		o, err := db.GetOrder(ctx, oid)
+		if err != nil {
+			return nil, err
+		}
+		if o.ProvisionerID != provID {
+			continue
+		}
+		if o.Status != acme.StatusPending && o.Status != acme.StatusReady {
+			continue
+		}
+		if o.ExpiresAt.Before(now) {
+			continue
+		}
+		if identifiersEqual(o.Identifiers, ids) {
+			return o, nil
+		}
+	}
+	return nil, nil
+}
+
+// identifiersEqual reports whether two canonicalized identifier slices are
+// identical.
+func identifiersEqual(a, b []acme.Identifier) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// capCertValidityWindow validates the order's NotBefore/NotAfter window and,
+// depending on the provisioner's StrictWindow setting, either clamps NotAfter
+// down to the provisioner's MaxTLSCertDuration (falling back to
+// defaultACMECertMaxDuration when unset) or rejects windows that exceed it.
+func capCertValidityWindow(o *acme.Order, prov acme.Provisioner, now time.Time) error {
+	skew := prov.NotBeforeSkew()
+	if skew <= 0 {
+		skew = defaultOrderNotBeforeSkew
+	}
+	if o.NotBefore.After(now.Add(skew)) {
+		return acme.NewError(acme.ErrorMalformedType, "notBefore is too far in the future")
+	}
+	if o.NotAfter.Before(now) {
+		return acme.NewError(acme.ErrorMalformedType, "notAfter is in the past")
+	}
+
+	max := prov.MaxTLSCertDuration()
+	if max <= 0 {
+		max = defaultACMECertMaxDuration
+	}
+	if d := o.NotAfter.Sub(o.NotBefore); d > max {
+		if prov.StrictWindow() {
+			return acme.NewError(acme.ErrorMalformedType,
+				"requested certificate validity of %s exceeds the provisioner maximum of %s", d, max)
+		}
+		o.NotAfter = o.NotBefore.Add(max)
+	}
+
+	// Re-check: clamping NotAfter down to NotBefore+max only produces a
+	// sane window when NotBefore itself is reasonably close to now. A
+	// NotBefore far enough in the past clamps to an already-expired
+	// NotAfter, which must be caught here rather than left to the caller.
+	if o.NotAfter.Before(now) {
+		return acme.NewError(acme.ErrorMalformedType, "notBefore is too far in the past")
+	}
+	return nil
+}
+
 // challengeTypes determines the types of challenges that should be used
 // for the ACME authorization request.
 func challengeTypes(az *acme.Authorization) []acme.ChallengeType {
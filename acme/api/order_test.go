@@ -0,0 +1,307 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/smallstep/certificates/acme"
+)
+
+// mockProvisioner is a minimal acme.Provisioner used to exercise
+// capCertValidityWindow without pulling in the real provisioner package.
+type mockProvisioner struct {
+	id              string
+	defaultDuration time.Duration
+	maxDuration     time.Duration
+	strictWindow    bool
+	notBeforeSkew   time.Duration
+}
+
+func (m *mockProvisioner) GetID() string                         { return m.id }
+func (m *mockProvisioner) DefaultTLSCertDuration() time.Duration { return m.defaultDuration }
+func (m *mockProvisioner) MaxTLSCertDuration() time.Duration     { return m.maxDuration }
+func (m *mockProvisioner) StrictWindow() bool                    { return m.strictWindow }
+func (m *mockProvisioner) NotBeforeSkew() time.Duration          { return m.notBeforeSkew }
+
+func TestToASCIIIdentifier(t *testing.T) {
+	type test struct {
+		value   string
+		want    string
+		wantErr bool
+	}
+	tests := map[string]test{
+		"ok/ascii":           {value: "example.com", want: "example.com"},
+		"ok/uppercase":       {value: "EXAMPLE.com", want: "example.com"},
+		"ok/unicode":         {value: "münchen.example", want: "xn--mnchen-3ya.example"},
+		"ok/wildcard":        {value: "*.münchen.example", want: "*.xn--mnchen-3ya.example"},
+		"fail/invalid-label": {value: "-example.com", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := toASCIIIdentifier(tc.value)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("toASCIIIdentifier(%q): expected error, got nil", tc.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("toASCIIIdentifier(%q): unexpected error: %v", tc.value, err)
+			}
+			if got != tc.want {
+				t.Errorf("toASCIIIdentifier(%q) = %q, want %q", tc.value, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewOrderRequest_Validate_CanonicalizesIdentifiers(t *testing.T) {
+	nor := &NewOrderRequest{
+		Identifiers: []acme.Identifier{
+			{Type: acme.DNS, Value: "example.com"},
+			{Type: acme.DNS, Value: "EXAMPLE.com"},
+			{Type: acme.DNS, Value: "münchen.example"},
+			{Type: acme.IP, Value: "::1"},
+		},
+	}
+	if err := nor.Validate(); err != nil {
+		t.Fatalf("Validate() unexpected error: %v", err)
+	}
+
+	want := []acme.Identifier{
+		{Type: acme.DNS, Value: "example.com"},
+		{Type: acme.DNS, Value: "xn--mnchen-3ya.example"},
+		{Type: acme.IP, Value: "::1"},
+	}
+	if len(nor.Identifiers) != len(want) {
+		t.Fatalf("Validate() produced %d identifiers, want %d: %+v", len(nor.Identifiers), len(want), nor.Identifiers)
+	}
+	for i := range want {
+		if nor.Identifiers[i] != want[i] {
+			t.Errorf("Identifiers[%d] = %+v, want %+v", i, nor.Identifiers[i], want[i])
+		}
+	}
+}
+
+func TestNewOrderRequest_Validate_RejectsEmpty(t *testing.T) {
+	nor := &NewOrderRequest{}
+	if err := nor.Validate(); err == nil {
+		t.Fatal("Validate() expected error for empty identifiers list, got nil")
+	}
+}
+
+func TestCapCertValidityWindow(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	prov := &mockProvisioner{id: "prov", maxDuration: 90 * 24 * time.Hour}
+
+	t.Run("ok/clamps-to-max", func(t *testing.T) {
+		o := &acme.Order{
+			NotBefore: now,
+			NotAfter:  now.Add(365 * 24 * time.Hour),
+		}
+		if err := capCertValidityWindow(o, prov, now); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := now.Add(90 * 24 * time.Hour); !o.NotAfter.Equal(want) {
+			t.Errorf("NotAfter = %v, want %v", o.NotAfter, want)
+		}
+	})
+
+	t.Run("fail/strict-window-rejects-excess", func(t *testing.T) {
+		strict := &mockProvisioner{id: "prov", maxDuration: 90 * 24 * time.Hour, strictWindow: true}
+		o := &acme.Order{
+			NotBefore: now,
+			NotAfter:  now.Add(365 * 24 * time.Hour),
+		}
+		if err := capCertValidityWindow(o, strict, now); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+
+	// Regression test: a NotBefore far enough in the past clamps NotAfter to
+	// an already-expired timestamp (NotBefore+max < now) even though both the
+	// future-skew and NotAfter-in-the-past guards pass on the *unclamped*
+	// window. capCertValidityWindow must re-check after clamping.
+	t.Run("fail/clamp-produces-expired-notafter", func(t *testing.T) {
+		o := &acme.Order{
+			NotBefore: now.Add(-1000 * 24 * time.Hour),
+			NotAfter:  now.Add(24 * time.Hour),
+		}
+		if err := capCertValidityWindow(o, prov, now); err == nil {
+			t.Fatalf("expected error, got nil (NotAfter clamped to %v)", o.NotAfter)
+		}
+	})
+
+	t.Run("fail/notBefore-too-far-in-future", func(t *testing.T) {
+		o := &acme.Order{
+			NotBefore: now.Add(2 * time.Hour),
+			NotAfter:  now.Add(24 * time.Hour),
+		}
+		if err := capCertValidityWindow(o, prov, now); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
+
+func TestFindExistingOrder(t *testing.T) {
+	// findExistingOrder compares ExpiresAt against clock.Now() internally, so
+	// fixtures must be relative to the real clock rather than a fixed date.
+	now := clock.Now()
+	ids := []acme.Identifier{{Type: acme.DNS, Value: "example.com"}}
+
+	matching := &acme.Order{ID: "match", ProvisionerID: "provA", Status: acme.StatusPending, ExpiresAt: now.Add(time.Hour), Identifiers: ids}
+	otherProvisioner := &acme.Order{ID: "other-prov", ProvisionerID: "provB", Status: acme.StatusPending, ExpiresAt: now.Add(time.Hour), Identifiers: ids}
+	expired := &acme.Order{ID: "expired", ProvisionerID: "provA", Status: acme.StatusPending, ExpiresAt: now.Add(-time.Hour), Identifiers: ids}
+
+	db := &acme.MockDB{
+		MockGetOrdersByAccountID: func(ctx context.Context, accID string) ([]string, error) {
+			return []string{matching.ID, otherProvisioner.ID, expired.ID}, nil
+		},
+		MockGetOrder: func(ctx context.Context, id string) (*acme.Order, error) {
+			switch id {
+			case matching.ID:
+				return matching, nil
+			case otherProvisioner.ID:
+				return otherProvisioner, nil
+			case expired.ID:
+				return expired, nil
+			}
+			return nil, nil
+		},
+	}
+
+	got, err := findExistingOrder(context.Background(), db, "acc", "provA", ids)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got == nil || got.ID != matching.ID {
+		t.Fatalf("findExistingOrder() = %+v, want order %q", got, matching.ID)
+	}
+
+	// An account with no order under this specific provisioner must not get
+	// back another provisioner's order (chunk0-4 review fix) or an expired
+	// one.
+	got, err = findExistingOrder(context.Background(), db, "acc", "provC", ids)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != nil {
+		t.Fatalf("findExistingOrder() = %+v, want nil", got)
+	}
+}
+
+// newOrdersByAccountIDRequest builds a GetOrdersByAccountID request for accID
+// with the given cursor ("" for none), whose context carries acc as the
+// authenticated account, db, and a no-op linker.
+func newOrdersByAccountIDRequest(accID, cursor string, acc *acme.Account, db acme.DB) *http.Request {
+	u := "/foo/account/" + accID + "/orders"
+	if cursor != "" {
+		u += "?cursor=" + cursor
+	}
+	req := httptest.NewRequest("GET", u, nil)
+
+	ctx := context.WithValue(req.Context(), accContextKey, acc)
+	ctx = acme.NewDatabaseContext(ctx, db)
+	ctx = acme.NewLinkerContext(ctx, &acme.MockLinker{
+		MockGetLink: func(ctx context.Context, typ acme.LinkType, inputs ...string) string {
+			return "https://example.com/acme/foo/order/" + inputs[0]
+		},
+		MockLinkOrder: func(ctx context.Context, o *acme.Order) {},
+	})
+
+	chiCtx := chi.NewRouteContext()
+	chiCtx.URLParams.Add("accID", accID)
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, chiCtx)
+
+	return req.WithContext(ctx)
+}
+
+func TestGetOrdersByAccountID(t *testing.T) {
+	now := clock.Now()
+	acc := &acme.Account{ID: "accA"}
+
+	// ordersByAccountIDPageSize+5 pending orders, plus one expired and one
+	// invalid order that the handler must filter out regardless of cursor.
+	var oids []string
+	orders := map[string]*acme.Order{}
+	for i := 0; i < ordersByAccountIDPageSize+5; i++ {
+		id := "ord" + strconv.Itoa(i)
+		oids = append(oids, id)
+		orders[id] = &acme.Order{ID: id, AccountID: acc.ID, Status: acme.StatusPending, ExpiresAt: now.Add(time.Hour)}
+	}
+	oids = append(oids, "ord-expired", "ord-invalid")
+	orders["ord-expired"] = &acme.Order{ID: "ord-expired", AccountID: acc.ID, Status: acme.StatusPending, ExpiresAt: now.Add(-time.Hour)}
+	orders["ord-invalid"] = &acme.Order{ID: "ord-invalid", AccountID: acc.ID, Status: acme.StatusInvalid, ExpiresAt: now.Add(time.Hour)}
+
+	db := &acme.MockDB{
+		MockGetOrdersByAccountID: func(ctx context.Context, accID string) ([]string, error) {
+			return oids, nil
+		},
+		MockGetOrder: func(ctx context.Context, id string) (*acme.Order, error) {
+			return orders[id], nil
+		},
+	}
+
+	t.Run("ok/first-page", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		GetOrdersByAccountID(w, newOrdersByAccountIDRequest(acc.ID, "", acc, db))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d; body=%s", w.Code, http.StatusOK, w.Body.String())
+		}
+		var body struct{ Orders []string }
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("unmarshal response: %v", err)
+		}
+		if len(body.Orders) != ordersByAccountIDPageSize {
+			t.Errorf("len(Orders) = %d, want %d", len(body.Orders), ordersByAccountIDPageSize)
+		}
+		if link := w.Header().Get("Link"); link == "" {
+			t.Error(`expected a Link: rel="next" header on a truncated first page, got none`)
+		}
+	})
+
+	t.Run("ok/second-page-no-more-link", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		GetOrdersByAccountID(w, newOrdersByAccountIDRequest(acc.ID, strconv.Itoa(ordersByAccountIDPageSize), acc, db))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d; body=%s", w.Code, http.StatusOK, w.Body.String())
+		}
+		var body struct{ Orders []string }
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("unmarshal response: %v", err)
+		}
+		// 5 remaining pending orders; ord-expired and ord-invalid are filtered.
+		if len(body.Orders) != 5 {
+			t.Errorf("len(Orders) = %d, want 5", len(body.Orders))
+		}
+		if link := w.Header().Get("Link"); link != "" {
+			t.Errorf("expected no Link header on the last page, got %q", link)
+		}
+	})
+
+	t.Run("fail/invalid-cursor", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		GetOrdersByAccountID(w, newOrdersByAccountIDRequest(acc.ID, "not-a-number", acc, db))
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d; body=%s", w.Code, http.StatusBadRequest, w.Body.String())
+		}
+	})
+
+	t.Run("fail/account-mismatch", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		GetOrdersByAccountID(w, newOrdersByAccountIDRequest("someone-else", "", acc, db))
+
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("status = %d, want %d; body=%s", w.Code, http.StatusUnauthorized, w.Body.String())
+		}
+	})
+}
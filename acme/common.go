@@ -0,0 +1,41 @@
+package acme
+
+import (
+	"context"
+	"time"
+)
+
+// Provisioner is the subset of the ACME provisioner interface touched by the
+// order/authorization flow in package api. The authorization/challenge
+// methods (AuthorizeOrderIdentifier, IsChallengeEnabled, ...) live alongside
+// the rest of the provisioner interface and are intentionally omitted here.
+type Provisioner interface {
+	GetID() string
+	// DefaultTLSCertDuration is the certificate lifetime used when the
+	// order doesn't request an explicit NotBefore/NotAfter.
+	DefaultTLSCertDuration() time.Duration
+	// MaxTLSCertDuration is the hard upper bound on the requested
+	// certificate lifetime. A value <= 0 means the caller should fall back
+	// to defaultACMECertMaxDuration.
+	MaxTLSCertDuration() time.Duration
+	// StrictWindow, when true, rejects a requested validity window that
+	// exceeds MaxTLSCertDuration instead of silently clamping it.
+	StrictWindow() bool
+	// NotBeforeSkew bounds how far into the future a client-supplied
+	// NotBefore may be. A value <= 0 means the caller should fall back to
+	// its own default.
+	NotBeforeSkew() time.Duration
+}
+
+type provisionerKey struct{}
+
+// NewProvisionerContext adds the given provisioner to the context.
+func NewProvisionerContext(ctx context.Context, v Provisioner) context.Context {
+	return context.WithValue(ctx, provisionerKey{}, v)
+}
+
+// ProvisionerFromContext returns the current provisioner from the given context.
+func ProvisionerFromContext(ctx context.Context) (v Provisioner, ok bool) {
+	v, ok = ctx.Value(provisionerKey{}).(Provisioner)
+	return
+}
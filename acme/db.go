@@ -0,0 +1,75 @@
+package acme
+
+import "context"
+
+// DB is the subset of the ACME persistence interface touched by the
+// order/authorization flow in package api. The account, nonce, and
+// certificate-revocation methods live alongside the rest of the ACME
+// persistence layer and are intentionally omitted here.
+type DB interface {
+	CreateOrder(ctx context.Context, o *Order) error
+	GetOrder(ctx context.Context, id string) (*Order, error)
+	// GetOrdersByAccountID returns the IDs of every order, of any status,
+	// created by the given account.
+	GetOrdersByAccountID(ctx context.Context, accountID string) ([]string, error)
+
+	CreateAuthorization(ctx context.Context, az *Authorization) error
+	CreateChallenge(ctx context.Context, ch *Challenge) error
+}
+
+type dbKey struct{}
+
+// NewDatabaseContext adds the given acme database to the context.
+func NewDatabaseContext(ctx context.Context, db DB) context.Context {
+	return context.WithValue(ctx, dbKey{}, db)
+}
+
+// DatabaseFromContext returns the current acme database from the given context.
+func DatabaseFromContext(ctx context.Context) (db DB, ok bool) {
+	db, ok = ctx.Value(dbKey{}).(DB)
+	return
+}
+
+// MustDatabaseFromContext returns the current acme database from the given
+// context. It will panic if it's not in the context.
+func MustDatabaseFromContext(ctx context.Context) DB {
+	db, ok := DatabaseFromContext(ctx)
+	if !ok {
+		panic("acme database is not in the context")
+	}
+	return db
+}
+
+// MockDB is a mock implementation of DB for use in tests.
+type MockDB struct {
+	MockCreateOrder          func(ctx context.Context, o *Order) error
+	MockGetOrder             func(ctx context.Context, id string) (*Order, error)
+	MockGetOrdersByAccountID func(ctx context.Context, accountID string) ([]string, error)
+	MockCreateAuthorization  func(ctx context.Context, az *Authorization) error
+	MockCreateChallenge      func(ctx context.Context, ch *Challenge) error
+}
+
+// CreateOrder mock.
+func (m *MockDB) CreateOrder(ctx context.Context, o *Order) error {
+	return m.MockCreateOrder(ctx, o)
+}
+
+// GetOrder mock.
+func (m *MockDB) GetOrder(ctx context.Context, id string) (*Order, error) {
+	return m.MockGetOrder(ctx, id)
+}
+
+// GetOrdersByAccountID mock.
+func (m *MockDB) GetOrdersByAccountID(ctx context.Context, accountID string) ([]string, error) {
+	return m.MockGetOrdersByAccountID(ctx, accountID)
+}
+
+// CreateAuthorization mock.
+func (m *MockDB) CreateAuthorization(ctx context.Context, az *Authorization) error {
+	return m.MockCreateAuthorization(ctx, az)
+}
+
+// CreateChallenge mock.
+func (m *MockDB) CreateChallenge(ctx context.Context, ch *Challenge) error {
+	return m.MockCreateChallenge(ctx, ch)
+}
@@ -0,0 +1,36 @@
+// Package nosql implements the nosql-backed acme.DB.
+package nosql
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/smallstep/nosql"
+)
+
+// ordersByAccountIDTable indexes the order IDs created by an account, so
+// that GetOrdersByAccountID doesn't need a full table scan. CreateOrder (not
+// reproduced in this trimmed checkout) is responsible for keeping this index
+// in sync as orders are created.
+var ordersByAccountIDTable = []byte("acme_order_ids_by_account")
+
+// GetOrdersByAccountID returns the IDs of every order owned by accID, as
+// recorded in the per-account index maintained by CreateOrder. DB itself,
+// and its other acme.DB methods, live alongside the rest of the nosql
+// backend and are intentionally omitted here.
+func (db *DB) GetOrdersByAccountID(ctx context.Context, accID string) ([]string, error) {
+	b, err := db.db.Get(ordersByAccountIDTable, []byte(accID))
+	switch {
+	case nosql.IsErrNotFound(err):
+		return []string{}, nil
+	case err != nil:
+		return nil, errors.Wrapf(err, "error loading order IDs for account %s", accID)
+	}
+
+	var oids []string
+	if err := json.Unmarshal(b, &oids); err != nil {
+		return nil, errors.Wrapf(err, "error unmarshaling order IDs for account %s", accID)
+	}
+	return oids, nil
+}
@@ -0,0 +1,82 @@
+package acme
+
+import "fmt"
+
+// ProblemType is the type of an ACME problem document, per RFC 8555 §6.7.
+// Only the values reached by the order/authorization flow in package api
+// are reproduced here; the rest of the ACME error catalog lives alongside
+// this file in the full repository and isn't reproduced in this trimmed
+// checkout.
+type ProblemType int
+
+const (
+	// ErrorAccountDoesNotExistType is an RFC 8555 "accountDoesNotExist" problem.
+	ErrorAccountDoesNotExistType ProblemType = iota + 1
+	// ErrorMalformedType is an RFC 8555 "malformed" problem.
+	ErrorMalformedType
+	// ErrorUnauthorizedType is an RFC 8555 "unauthorized" problem.
+	ErrorUnauthorizedType
+	// ErrorServerInternalType is an RFC 8555 "serverInternal" problem.
+	ErrorServerInternalType
+)
+
+// statusCodes maps each ProblemType to the HTTP status code it renders as.
+var statusCodes = map[ProblemType]int{
+	ErrorAccountDoesNotExistType: 400,
+	ErrorMalformedType:           400,
+	ErrorUnauthorizedType:        401,
+	ErrorServerInternalType:      500,
+}
+
+// Error is a subset of the ACME problem-document type: just enough for the
+// order/authorization flow in package api to construct and wrap errors. The
+// render.RenderableError/Subproblem machinery lives alongside this file in
+// the full repository and isn't reproduced in this trimmed checkout.
+type Error struct {
+	Type   ProblemType
+	Detail string
+	Status int
+	Err    error
+}
+
+// NewError creates a new Error.
+func NewError(pt ProblemType, msg string, args ...interface{}) *Error {
+	return &Error{Type: pt, Detail: fmt.Sprintf(msg, args...), Status: statusCodes[pt]}
+}
+
+// NewErrorISE creates a new ErrorServerInternalType Error.
+func NewErrorISE(msg string, args ...interface{}) *Error {
+	return NewError(ErrorServerInternalType, msg, args...)
+}
+
+// WrapError wraps err in a new Error, unless err already is one, in which
+// case its underlying error is wrapped in place.
+func WrapError(pt ProblemType, err error, msg string, args ...interface{}) *Error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(*Error); ok {
+		e.Err = fmt.Errorf("%s: %w", fmt.Sprintf(msg, args...), e.Err)
+		return e
+	}
+	return &Error{Type: pt, Detail: fmt.Sprintf(msg, args...), Status: statusCodes[pt], Err: err}
+}
+
+// WrapErrorISE wraps err as an ErrorServerInternalType Error.
+func WrapErrorISE(err error, msg string, args ...interface{}) *Error {
+	return WrapError(ErrorServerInternalType, err, msg, args...)
+}
+
+// StatusCode returns the HTTP status code e should be rendered with,
+// implementing render's StatusCodedError interface.
+func (e *Error) StatusCode() int {
+	return e.Status
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Detail
+	}
+	return e.Err.Error()
+}
@@ -0,0 +1,79 @@
+package acme
+
+import "time"
+
+// IdentifierType represents the type of identifier (dns, ip) that an ACME
+// order or authorization is for.
+type IdentifierType string
+
+const (
+	// IP is the IP identifier type.
+	IP IdentifierType = "ip"
+	// DNS is the DNS identifier type.
+	DNS IdentifierType = "dns"
+)
+
+// Identifier represents an ACME order identifier, per RFC 8555 §9.7.7.
+type Identifier struct {
+	Type  IdentifierType `json:"type"`
+	Value string         `json:"value"`
+}
+
+// ChallengeType represents the type of an ACME challenge.
+type ChallengeType string
+
+const (
+	// HTTP01 is the http-01 ACME challenge type.
+	HTTP01 ChallengeType = "http-01"
+	// DNS01 is the dns-01 ACME challenge type.
+	DNS01 ChallengeType = "dns-01"
+	// TLSALPN01 is the tls-alpn-01 ACME challenge type.
+	TLSALPN01 ChallengeType = "tls-alpn-01"
+)
+
+// Challenge is a subset of the ACME Challenge resource (RFC 8555 §8): just
+// enough for newAuthorization to create one. The validation/retry machinery
+// lives alongside the rest of the ACME challenge handling in the full
+// repository and isn't reproduced in this trimmed checkout.
+type Challenge struct {
+	ID        string        `json:"-"`
+	AccountID string        `json:"-"`
+	Type      ChallengeType `json:"type"`
+	Status    Status        `json:"status"`
+	Token     string        `json:"token"`
+	Value     string        `json:"-"`
+}
+
+// Authorization is a subset of the ACME Authorization resource (RFC 8555
+// §7.1.4): just enough for the order/challenge flow in package api. The
+// validation/expiry machinery lives alongside the rest of the ACME
+// authorization handling in the full repository and isn't reproduced here.
+type Authorization struct {
+	ID         string       `json:"-"`
+	AccountID  string       `json:"-"`
+	Identifier Identifier   `json:"identifier"`
+	Status     Status       `json:"status"`
+	ExpiresAt  time.Time    `json:"expires"`
+	Wildcard   bool         `json:"wildcard"`
+	Token      string       `json:"-"`
+	Challenges []*Challenge `json:"challenges"`
+}
+
+// Order is a subset of the ACME Order resource (RFC 8555 §7.1.3): just
+// enough for the order/authorization flow in package api. The
+// certificate-issuance machinery (Finalize) and finer status transitions
+// (UpdateStatus) live alongside the rest of the ACME order handling in the
+// full repository and aren't reproduced in this trimmed checkout.
+type Order struct {
+	ID               string       `json:"-"`
+	AccountID        string       `json:"-"`
+	ProvisionerID    string       `json:"-"`
+	Status           Status       `json:"status"`
+	ExpiresAt        time.Time    `json:"expires"`
+	Identifiers      []Identifier `json:"identifiers"`
+	NotBefore        time.Time    `json:"notBefore"`
+	NotAfter         time.Time    `json:"notAfter"`
+	Error            *Error       `json:"error,omitempty"`
+	AuthorizationIDs []string     `json:"-"`
+	CertificateID    string       `json:"-"`
+}
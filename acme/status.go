@@ -0,0 +1,19 @@
+package acme
+
+// Status represents an ACME status, per RFC 8555 §7.1.6. Only the values
+// reached by the order/authorization flow in package api are reproduced
+// here.
+type Status string
+
+var (
+	// StatusValid -- valid
+	StatusValid = Status("valid")
+	// StatusInvalid -- invalid
+	StatusInvalid = Status("invalid")
+	// StatusPending -- pending; e.g. an Order that is not ready to be finalized.
+	StatusPending = Status("pending")
+	// StatusDeactivated -- deactivated; e.g. for an Account that is no longer valid.
+	StatusDeactivated = Status("deactivated")
+	// StatusReady -- ready; e.g. for an Order that is ready to be finalized.
+	StatusReady = Status("ready")
+)
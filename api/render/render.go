@@ -0,0 +1,49 @@
+// Package render implements functionality related to response rendering.
+//
+// This is a trimmed subset of the full repository's render package: just
+// JSON/JSONStatus/Error, the pieces touched by the ACME order/authorization
+// flow in acme/api. The protobuf and structured-logging helpers live
+// alongside this file in the full repository and aren't reproduced here.
+package render
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// JSON is shorthand for JSONStatus(w, v, http.StatusOK).
+func JSON(w http.ResponseWriter, v interface{}) {
+	JSONStatus(w, v, http.StatusOK)
+}
+
+// JSONStatus marshals v into w and sets the status code of w to status.
+func JSONStatus(w http.ResponseWriter, v interface{}, status int) {
+	setContentTypeUnlessPresent(w, "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// statusCodedError is implemented by errors that know their own HTTP status
+// code, such as *acme.Error.
+type statusCodedError interface {
+	error
+	StatusCode() int
+}
+
+// Error marshals the JSON representation of err to w, using err's own status
+// code when it implements statusCodedError.
+func Error(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if sc, ok := err.(statusCodedError); ok && sc.StatusCode() != 0 {
+		status = sc.StatusCode()
+	}
+	JSONStatus(w, err, status)
+}
+
+func setContentTypeUnlessPresent(w http.ResponseWriter, contentType string) {
+	const header = "Content-Type"
+	h := w.Header()
+	if _, ok := h[header]; !ok {
+		h.Set(header, contentType)
+	}
+}
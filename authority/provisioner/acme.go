@@ -0,0 +1,60 @@
+package provisioner
+
+import "time"
+
+// ACME is the provisioner type used to register and bootstrap ACME
+// challenge-driven certificate authorization flows. Only the fields touched
+// by the acme/api order-validity window live here; the rest of the ACME
+// provisioner (challenge toggles, EAB, policy, ...) lives alongside it in
+// the full repository and isn't reproduced in this trimmed checkout.
+type ACME struct {
+	ID   string `json:"-"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+
+	// CertDuration is the certificate lifetime used when an order doesn't
+	// request an explicit NotBefore/NotAfter.
+	CertDuration Duration `json:"tlsCertDuration,omitempty"`
+
+	// MaxCertDuration caps the requested certificate validity window,
+	// analogous to Vault PKI ACME's maxAcmeCertTTL. Defaults to 90 days
+	// when unset.
+	MaxCertDuration Duration `json:"maxTLSCertDuration,omitempty"`
+
+	// StrictCertWindow, when true, rejects a requested validity window that
+	// exceeds MaxCertDuration instead of clamping NotAfter down to it.
+	StrictCertWindow bool `json:"strictWindow,omitempty"`
+
+	// CertNotBeforeSkew bounds how far into the future a client-supplied
+	// NotBefore may be before the order is rejected.
+	CertNotBeforeSkew Duration `json:"orderNotBeforeSkew,omitempty"`
+}
+
+// GetID returns the provisioner unique identifier.
+func (p *ACME) GetID() string {
+	return p.ID
+}
+
+// DefaultTLSCertDuration returns the default TLS cert duration enforced by
+// the provisioner.
+func (p *ACME) DefaultTLSCertDuration() time.Duration {
+	return p.CertDuration.Value()
+}
+
+// MaxTLSCertDuration returns the maximum TLS cert duration enforced by the
+// provisioner, or 0 if unset (callers fall back to their own default).
+func (p *ACME) MaxTLSCertDuration() time.Duration {
+	return p.MaxCertDuration.Value()
+}
+
+// StrictWindow reports whether a validity window exceeding
+// MaxTLSCertDuration should be rejected rather than clamped.
+func (p *ACME) StrictWindow() bool {
+	return p.StrictCertWindow
+}
+
+// NotBeforeSkew returns how far into the future a client-supplied NotBefore
+// may be, or 0 if unset (callers fall back to their own default).
+func (p *ACME) NotBeforeSkew() time.Duration {
+	return p.CertNotBeforeSkew.Value()
+}